@@ -12,9 +12,15 @@ import (
 
 	"github.com/joho/godotenv" // Importa a nova biblioteca
 	"github.com/luiszkm/go-todolist/internal/api"
+	"github.com/luiszkm/go-todolist/internal/auth"
+	"github.com/luiszkm/go-todolist/internal/operations"
 	"github.com/luiszkm/go-todolist/internal/storage"
 )
 
+// defaultOperationsShutdownTimeout é usado quando OPERATIONS_SHUTDOWN_TIMEOUT
+// não está definida ou é inválida.
+const defaultOperationsShutdownTimeout = 30 * time.Second
+
 func main() {
 	// Inicializa o logger estruturado.
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
@@ -45,10 +51,17 @@ func main() {
 	}
 	defer store.Close()
 
+	authStore := auth.NewPostgresStore(store.DB(), logger)
+	authServer := auth.NewServer(logger, authStore)
+
+	broker := operations.NewBroker()
+	opsRegistry := operations.NewRegistry(broker)
+
 	serverAddr := ":8080"
-	apiServer := api.NewAPIServer(serverAddr, logger, store)
+	apiServer := api.NewAPIServer(serverAddr, logger, store, opsRegistry, broker)
 	mux := http.NewServeMux()
-	apiServer.RegisterRoutes(mux)
+	authServer.RegisterRoutes(mux)
+	apiServer.RegisterRoutes(mux, authServer.AuthRequired)
 
 	httpServer := &http.Server{
 		Addr:    serverAddr,
@@ -68,6 +81,34 @@ func main() {
 	stop()
 	logger.Info("servidor recebendo sinal para desligar")
 
+	// Espera as operações assíncronas pendentes com o servidor HTTP ainda no
+	// ar, para que clientes consigam observar sua conclusão via polling em
+	// /operations/{id} ou via /events. Só depois disso o listener é fechado:
+	// esperar com o servidor já desligado apenas atrasaria a saída do
+	// processo sem que nenhum cliente pudesse ver o resultado.
+	opsTimeout := defaultOperationsShutdownTimeout
+	if v := os.Getenv("OPERATIONS_SHUTDOWN_TIMEOUT"); v != "" {
+		if parsed, err := time.ParseDuration(v); err == nil {
+			opsTimeout = parsed
+		} else {
+			logger.Error("OPERATIONS_SHUTDOWN_TIMEOUT inválida, usando o padrão", "valor", v, "padrão", defaultOperationsShutdownTimeout)
+		}
+	}
+
+	opsCtx, opsCancel := context.WithTimeout(context.Background(), opsTimeout)
+	defer opsCancel()
+
+	logger.Info("aguardando operações assíncronas pendentes", "timeout", opsTimeout)
+	if err := opsRegistry.Wait(opsCtx); err != nil {
+		logger.Error("nem todas as operações pendentes terminaram antes do timeout", "erro", err)
+	}
+
+	// Só agora sinalizamos aos handlers de longa duração (SSE) para
+	// encerrarem e fechamos o servidor: http.Server.Shutdown não cancela o
+	// contexto de requisições em andamento, então um cliente de /events
+	// conectado manteria a conexão "ativa" pelo timeout inteiro.
+	apiServer.Shutdown()
+
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/luiszkm/go-todolist/internal/todo"
+)
+
+// bulkCreateResult é o resultado publicado em Operation.Result quando uma
+// operação do tipo "bulk_create_todos" termina.
+type bulkCreateResult struct {
+	Created []todo.Todo       `json:"created"`
+	Failed  []bulkCreateError `json:"failed,omitempty"`
+}
+
+// bulkCreateError associa o índice do item no payload original ao erro que
+// impediu sua criação, já que um item inválido não deve derrubar o lote
+// inteiro.
+type bulkCreateError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// handleBulkCreateTodos aceita um array JSON de to-dos e os cria de forma
+// assíncrona, retornando 202 Accepted imediatamente com o ID da operação em
+// vez de bloquear a requisição até todo o lote ser processado.
+func (s *APIServer) handleBulkCreateTodos(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, s.logger, http.StatusMethodNotAllowed, "Método não permitido")
+		return
+	}
+
+	owner, ok := ownerID(r)
+	if !ok {
+		respondWithError(w, s.logger, http.StatusUnauthorized, "Não autorizado")
+		return
+	}
+
+	var items []todo.Todo
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		respondWithError(w, s.logger, http.StatusBadRequest, "Payload da requisição inválido")
+		return
+	}
+
+	op := s.ops.Run(owner, "bulk_create_todos", func(progress func(int)) (any, error) {
+		result := bulkCreateResult{}
+		for i, item := range items {
+			// A operação roda além do ciclo de vida da requisição HTTP, então
+			// não podemos usar r.Context() aqui.
+			created, err := s.store.CreateTodo(context.Background(), owner, item)
+			if err != nil {
+				result.Failed = append(result.Failed, bulkCreateError{Index: i, Error: err.Error()})
+			} else {
+				result.Created = append(result.Created, *created)
+			}
+			progress((i + 1) * 100 / len(items))
+		}
+		return result, nil
+	})
+
+	w.Header().Set("Location", fmt.Sprintf("/operations/%s", op.ID))
+	respondWithJSON(w, http.StatusAccepted, map[string]string{"operation_id": op.ID})
+}
+
+// handleGetOperation retorna o estado atual de uma operação assíncrona
+// pertencente ao usuário autenticado. Uma operação de outro usuário é
+// tratada como inexistente (404), no mesmo espírito de GetTodo.
+func (s *APIServer) handleGetOperation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, s.logger, http.StatusMethodNotAllowed, "Método não permitido")
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/operations/")
+	if id == "" {
+		respondWithError(w, s.logger, http.StatusBadRequest, "ID da operação não pode ser vazio")
+		return
+	}
+
+	owner, ok := ownerID(r)
+	if !ok {
+		respondWithError(w, s.logger, http.StatusUnauthorized, "Não autorizado")
+		return
+	}
+
+	op, ok := s.ops.Get(id)
+	if !ok || op.OwnerID != owner {
+		respondWithError(w, s.logger, http.StatusNotFound, "Operação não encontrada")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, op.View())
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBulkCreateTodosAsync(t *testing.T) {
+	srv := newIsolationTestServer()
+	defer srv.Close()
+
+	token := registerAndLogin(t, srv.URL, "carol@example.com")
+
+	body, _ := json.Marshal([]map[string]string{
+		{"title": "comprar leite"},
+		{"title": "lavar o carro"},
+	})
+	resp := authedRequest(t, http.MethodPost, srv.URL+"/todos/bulk", token, body)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusAccepted)
+	}
+	if loc := resp.Header.Get("Location"); loc == "" {
+		t.Fatal("resposta não possui header Location")
+	}
+
+	var accepted struct {
+		OperationID string `json:"operation_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&accepted); err != nil {
+		t.Fatalf("falha ao decodificar resposta: %v", err)
+	}
+	if accepted.OperationID == "" {
+		t.Fatal("operation_id não deveria ser vazio")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		opResp := authedRequest(t, http.MethodGet, srv.URL+"/operations/"+accepted.OperationID, token, nil)
+		var view struct {
+			Status string `json:"status"`
+		}
+		json.NewDecoder(opResp.Body).Decode(&view)
+		opResp.Body.Close()
+
+		if view.Status == "done" || view.Status == "failed" {
+			if view.Status != "done" {
+				t.Fatalf("status da operação = %q, want %q", view.Status, "done")
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("operação não terminou a tempo")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGetOperationCrossUserIsolation(t *testing.T) {
+	srv := newIsolationTestServer()
+	defer srv.Close()
+
+	tokenA := registerAndLogin(t, srv.URL, "erin@example.com")
+	tokenB := registerAndLogin(t, srv.URL, "frank@example.com")
+
+	body, _ := json.Marshal([]map[string]string{{"title": "segredo da erin"}})
+	resp := authedRequest(t, http.MethodPost, srv.URL+"/todos/bulk", tokenA, body)
+	var accepted struct {
+		OperationID string `json:"operation_id"`
+	}
+	json.NewDecoder(resp.Body).Decode(&accepted)
+	resp.Body.Close()
+
+	// Frank não deve conseguir ver a operação da Erin; deve ser um 404 (não
+	// 403) para não vazar que o recurso existe, no mesmo espírito de
+	// TestCrossUserIsolation.
+	opResp := authedRequest(t, http.MethodGet, srv.URL+"/operations/"+accepted.OperationID, tokenB, nil)
+	defer opResp.Body.Close()
+	if opResp.StatusCode != http.StatusNotFound {
+		t.Fatalf("get de operação de outro usuário: status = %d, want %d", opResp.StatusCode, http.StatusNotFound)
+	}
+
+	// Erin consegue ver a própria operação.
+	opResp2 := authedRequest(t, http.MethodGet, srv.URL+"/operations/"+accepted.OperationID, tokenA, nil)
+	defer opResp2.Body.Close()
+	if opResp2.StatusCode != http.StatusOK {
+		t.Fatalf("get da dona: status = %d, want %d", opResp2.StatusCode, http.StatusOK)
+	}
+}
+
+func TestGetOperationNotFound(t *testing.T) {
+	srv := newIsolationTestServer()
+	defer srv.Close()
+
+	token := registerAndLogin(t, srv.URL, "dave@example.com")
+
+	resp := authedRequest(t, http.MethodGet, srv.URL+"/operations/does-not-exist", token, nil)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
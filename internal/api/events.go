@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// handleEvents expõe um stream de Server-Sent Events com atualizações de
+// operações assíncronas (POST /todos/bulk e similares). Cada cliente
+// conectado é um assinante independente do Broker, restrito às operações do
+// próprio usuário autenticado; assinantes lentos são descartados pelo
+// próprio Broker em vez de travar os demais.
+func (s *APIServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, s.logger, http.StatusMethodNotAllowed, "Método não permitido")
+		return
+	}
+
+	owner, ok := ownerID(r)
+	if !ok {
+		respondWithError(w, s.logger, http.StatusUnauthorized, "Não autorizado")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(w, s.logger, http.StatusInternalServerError, "Streaming não suportado")
+		return
+	}
+
+	sub, cancel := s.broker.Subscribe(owner)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		case evt, open := <-sub:
+			if !open {
+				return
+			}
+			payload, err := json.Marshal(evt.Data)
+			if err != nil {
+				s.logger.Error("falha ao serializar evento", "erro", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", evt.Type, payload)
+			flusher.Flush()
+		}
+	}
+}
@@ -1,35 +1,91 @@
 package api
 
 import (
-	"encoding/json"
 	"log/slog"
 	"net/http"
 	"strings"
 
+	"github.com/luiszkm/go-todolist/internal/api/versioning"
+	"github.com/luiszkm/go-todolist/internal/auth"
+	"github.com/luiszkm/go-todolist/internal/operations"
 	"github.com/luiszkm/go-todolist/internal/storage"
-	"github.com/luiszkm/go-todolist/internal/todo"
 )
 
+// respondWithStoreErr mapeia um erro vindo do storage para a resposta HTTP
+// correspondente usando httpStatusFromErr, prefixando a mensagem com o
+// contexto da operação que falhou.
+func (s *APIServer) respondWithStoreErr(w http.ResponseWriter, op string, err error) {
+	code, msg := httpStatusFromErr(err)
+	respondWithError(w, s.logger, code, op+": "+msg)
+}
+
+// ownerID extrai o ID do usuário autenticado do contexto da requisição. As
+// rotas de /todos são sempre montadas atrás de auth.AuthRequired, então a
+// ausência de um Principal indica um erro de configuração das rotas, não uma
+// requisição não autenticada.
+func ownerID(r *http.Request) (string, bool) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		return "", false
+	}
+	return principal.UserID, true
+}
+
 // APIServer encapsula as dependências do servidor da API, como o logger e o storage.
 type APIServer struct {
 	addr   string
 	logger *slog.Logger
 	store  storage.Store
+	ops    *operations.Registry
+	broker *operations.Broker
+	done   chan struct{}
 }
 
-// NewAPIServer cria uma nova instância do nosso servidor da API.
-func NewAPIServer(addr string, logger *slog.Logger, store storage.Store) *APIServer {
+// NewAPIServer cria uma nova instância do nosso servidor da API. ops e
+// broker coordenam as operações assíncronas expostas por /todos/bulk,
+// /operations/{id} e /events.
+func NewAPIServer(addr string, logger *slog.Logger, store storage.Store, ops *operations.Registry, broker *operations.Broker) *APIServer {
 	return &APIServer{
 		addr:   addr,
 		logger: logger,
 		store:  store,
+		ops:    ops,
+		broker: broker,
+		done:   make(chan struct{}),
 	}
 }
 
-// RegisterRoutes registra todos os handlers da nossa API no mux.
-func (s *APIServer) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/todos", s.handleTodos)
-	mux.HandleFunc("/todos/", s.handleTodoByID) // Note a barra no final para capturar /todos/qualquer-coisa
+// Shutdown sinaliza para os handlers de longa duração (atualmente apenas
+// /events) que o servidor está encerrando, para que eles retornem por conta
+// própria em vez de manter a conexão HTTP ativa. http.Server.Shutdown não
+// cancela o contexto de requisições em andamento, então sem esse sinal um
+// cliente SSE conectado impediria o graceful shutdown de terminar antes do
+// timeout. Deve ser chamado uma única vez, antes de httpServer.Shutdown.
+func (s *APIServer) Shutdown() {
+	close(s.done)
+}
+
+// RegisterRoutes registra todos os handlers da nossa API no mux, exigindo
+// autenticação via authRequired em todas as rotas de /todos, /operations e
+// /events. As rotas de todo são montadas duas vezes - em /v1/todos e em
+// /todos, este último um alias para a versão default - ambas passando pelo
+// versioning.Middleware para que o Accept header também seja respeitado sob
+// o prefixo /todos.
+func (s *APIServer) RegisterRoutes(mux *http.ServeMux, authRequired func(http.Handler) http.Handler) {
+	todosHandler := versioning.Middleware(authRequired(http.HandlerFunc(s.handleTodos)))
+	todoByIDHandler := versioning.Middleware(authRequired(http.HandlerFunc(s.handleTodoByID)))
+	bulkHandler := versioning.Middleware(authRequired(http.HandlerFunc(s.handleBulkCreateTodos)))
+
+	mux.Handle("/todos", todosHandler)
+	mux.Handle("/todos/", todoByIDHandler) // Note a barra no final para capturar /todos/qualquer-coisa
+	mux.Handle("/todos/bulk", bulkHandler)
+	mux.Handle("/v1/todos", todosHandler)
+	mux.Handle("/v1/todos/", todoByIDHandler)
+	mux.Handle("/v1/todos/bulk", bulkHandler)
+
+	mux.Handle("/operations/", authRequired(http.HandlerFunc(s.handleGetOperation)))
+	mux.Handle("/events", authRequired(http.HandlerFunc(s.handleEvents)))
+	mux.HandleFunc("/version", s.handleVersion)
 }
 
 // handleTodos é um dispatcher que decide entre Listar e Criar baseado no método HTTP.
@@ -46,8 +102,9 @@ func (s *APIServer) handleTodos(w http.ResponseWriter, r *http.Request) {
 
 // handleTodoByID é um dispatcher para rotas que incluem um ID.
 func (s *APIServer) handleTodoByID(w http.ResponseWriter, r *http.Request) {
-	// Extrai o ID da URL. Ex: /todos/uuid-vai-aqui
-	id := strings.TrimPrefix(r.URL.Path, "/todos/")
+	// Extrai o ID da URL. Ex: /todos/uuid-vai-aqui ou /v1/todos/uuid-vai-aqui
+	id := strings.TrimPrefix(r.URL.Path, "/v1/todos/")
+	id = strings.TrimPrefix(id, "/todos/")
 	if id == "" {
 		respondWithError(w, s.logger, http.StatusBadRequest, "ID do To-Do não pode ser vazio")
 		return
@@ -66,8 +123,10 @@ func (s *APIServer) handleTodoByID(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *APIServer) handleCreateTodo(w http.ResponseWriter, r *http.Request) {
-	var newTodo todo.Todo
-	if err := json.NewDecoder(r.Body).Decode(&newTodo); err != nil {
+	apiVersion := versioning.FromContext(r.Context())
+
+	newTodo, err := versioning.DecodeTodo(r.Body, apiVersion)
+	if err != nil {
 		respondWithError(w, s.logger, http.StatusBadRequest, "Payload da requisição inválido")
 		return
 	}
@@ -77,47 +136,41 @@ func (s *APIServer) handleCreateTodo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	createdTodo, err := s.store.CreateTodo(r.Context(), newTodo)
-	if err != nil {
-		respondWithError(w, s.logger, http.StatusInternalServerError, "Falha ao criar to-do")
+	owner, ok := ownerID(r)
+	if !ok {
+		respondWithError(w, s.logger, http.StatusUnauthorized, "Não autorizado")
 		return
 	}
 
-	respondWithJSON(w, http.StatusCreated, createdTodo)
-}
-
-func (s *APIServer) handleListTodos(w http.ResponseWriter, r *http.Request) {
-	todos, err := s.store.ListTodos(r.Context())
+	createdTodo, err := s.store.CreateTodo(r.Context(), owner, newTodo)
 	if err != nil {
-		respondWithError(w, s.logger, http.StatusInternalServerError, "Falha ao listar to-dos")
+		s.respondWithStoreErr(w, "Falha ao criar to-do", err)
 		return
 	}
 
-	// Retorna uma lista vazia em vez de nula se não houver tarefas.
-	if todos == nil {
-		todos = []todo.Todo{}
-	}
-
-	respondWithJSON(w, http.StatusOK, todos)
+	respondWithJSON(w, http.StatusCreated, versioning.ToWire(apiVersion, *createdTodo))
 }
 
 func (s *APIServer) handleGetTodo(w http.ResponseWriter, r *http.Request, id string) {
-	foundTodo, err := s.store.GetTodo(r.Context(), id)
+	owner, ok := ownerID(r)
+	if !ok {
+		respondWithError(w, s.logger, http.StatusUnauthorized, "Não autorizado")
+		return
+	}
+
+	foundTodo, err := s.store.GetTodo(r.Context(), owner, id)
 	if err != nil {
-		// Podemos ser mais específicos aqui, verificando se o erro é 'não encontrado'.
-		if strings.Contains(err.Error(), "não encontrado") {
-			respondWithError(w, s.logger, http.StatusNotFound, "To-do não encontrado")
-		} else {
-			respondWithError(w, s.logger, http.StatusInternalServerError, "Falha ao buscar to-do")
-		}
+		s.respondWithStoreErr(w, "Falha ao buscar to-do", err)
 		return
 	}
-	respondWithJSON(w, http.StatusOK, foundTodo)
+	respondWithJSON(w, http.StatusOK, versioning.ToWire(versioning.FromContext(r.Context()), *foundTodo))
 }
 
 func (s *APIServer) handleUpdateTodo(w http.ResponseWriter, r *http.Request, id string) {
-	var updatedTodo todo.Todo
-	if err := json.NewDecoder(r.Body).Decode(&updatedTodo); err != nil {
+	apiVersion := versioning.FromContext(r.Context())
+
+	updatedTodo, err := versioning.DecodeTodo(r.Body, apiVersion)
+	if err != nil {
 		respondWithError(w, s.logger, http.StatusBadRequest, "Payload da requisição inválido")
 		return
 	}
@@ -127,27 +180,52 @@ func (s *APIServer) handleUpdateTodo(w http.ResponseWriter, r *http.Request, id
 		return
 	}
 
-	result, err := s.store.UpdateTodo(r.Context(), id, updatedTodo)
+	owner, ok := ownerID(r)
+	if !ok {
+		respondWithError(w, s.logger, http.StatusUnauthorized, "Não autorizado")
+		return
+	}
+
+	result, err := s.store.UpdateTodo(r.Context(), owner, id, updatedTodo)
 	if err != nil {
-		if strings.Contains(err.Error(), "não encontrado") {
-			respondWithError(w, s.logger, http.StatusNotFound, "To-do não encontrado para atualizar")
-		} else {
-			respondWithError(w, s.logger, http.StatusInternalServerError, "Falha ao atualizar to-do")
-		}
+		s.respondWithStoreErr(w, "Falha ao atualizar to-do", err)
 		return
 	}
-	respondWithJSON(w, http.StatusOK, result)
+	respondWithJSON(w, http.StatusOK, versioning.ToWire(apiVersion, *result))
 }
 
 func (s *APIServer) handleDeleteTodo(w http.ResponseWriter, r *http.Request, id string) {
-	err := s.store.DeleteTodo(r.Context(), id)
+	owner, ok := ownerID(r)
+	if !ok {
+		respondWithError(w, s.logger, http.StatusUnauthorized, "Não autorizado")
+		return
+	}
+
+	err := s.store.DeleteTodo(r.Context(), owner, id)
 	if err != nil {
-		if strings.Contains(err.Error(), "não encontrado") {
-			respondWithError(w, s.logger, http.StatusNotFound, "To-do não encontrado para deletar")
-		} else {
-			respondWithError(w, s.logger, http.StatusInternalServerError, "Falha ao deletar to-do")
-		}
+		s.respondWithStoreErr(w, "Falha ao deletar to-do", err)
 		return
 	}
 	respondWithJSON(w, http.StatusNoContent, nil)
 }
+
+// versionInfo é o corpo de resposta de GET /version.
+type versionInfo struct {
+	APIVersions []versioning.Version `json:"api_versions"`
+	Default     versioning.Version   `json:"default"`
+	Min         versioning.Version   `json:"min"`
+}
+
+// handleVersion anuncia as versões da API suportadas por este binário, para
+// que clientes possam descobrir qual usar antes de negociar via /v1 ou Accept.
+func (s *APIServer) handleVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondWithError(w, s.logger, http.StatusMethodNotAllowed, "Método não permitido")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, versionInfo{
+		APIVersions: versioning.Supported(),
+		Default:     versioning.DefaultVersion,
+		Min:         versioning.MinVersion,
+	})
+}
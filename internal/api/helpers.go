@@ -4,8 +4,16 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+
+	"github.com/luiszkm/go-todolist/internal/storage/errdefs"
 )
 
+// httpStatusFromErr mapeia um erro da camada de storage para um status HTTP e
+// uma mensagem segura para o cliente, delegando para errdefs.HTTPStatus.
+func httpStatusFromErr(err error) (int, string) {
+	return errdefs.HTTPStatus(err)
+}
+
 // respondWithError envia uma resposta de erro JSON.
 func respondWithError(w http.ResponseWriter, logger *slog.Logger, code int, message string) {
 	// Logamos o erro internamente antes de responder ao cliente.
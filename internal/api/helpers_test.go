@@ -0,0 +1,62 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/luiszkm/go-todolist/internal/storage/errdefs"
+)
+
+func TestHttpStatusFromErr(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{
+			name:     "not found",
+			err:      errdefs.NewNotFound(fmt.Errorf("todo com id 'x' não encontrado: %w", sql.ErrNoRows)),
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "conflict",
+			err:      errdefs.NewConflict(errors.New("violação de unicidade")),
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "invalid argument",
+			err:      errdefs.NewInvalidArgument(errors.New("violação de chave estrangeira")),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unauthorized",
+			err:      errdefs.NewUnauthorized(errors.New("token inválido")),
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "internal fallback",
+			err:      errors.New("algo deu errado"),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "wrapped several layers still resolves",
+			err:      fmt.Errorf("handler: %w", fmt.Errorf("service: %w", errdefs.NewNotFound(sql.ErrNoRows))),
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCode, gotMsg := httpStatusFromErr(tt.err)
+			if gotCode != tt.wantCode {
+				t.Errorf("httpStatusFromErr() code = %d, want %d", gotCode, tt.wantCode)
+			}
+			if gotMsg == "" {
+				t.Errorf("httpStatusFromErr() message deve ser não vazia")
+			}
+		})
+	}
+}
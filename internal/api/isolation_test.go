@@ -0,0 +1,301 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luiszkm/go-todolist/internal/auth"
+	"github.com/luiszkm/go-todolist/internal/operations"
+	"github.com/luiszkm/go-todolist/internal/storage"
+	"github.com/luiszkm/go-todolist/internal/storage/errdefs"
+	"github.com/luiszkm/go-todolist/internal/todo"
+)
+
+// fakeAuthStore é uma implementação em memória de auth.Store usada para
+// exercitar o fluxo de autenticação e o isolamento entre usuários sem um
+// banco de dados real.
+type fakeAuthStore struct {
+	mu      sync.Mutex
+	nextID  int
+	users   map[string]*auth.User // por email
+	tokens  map[string]string     // token -> userID
+	revoked map[string]bool
+}
+
+func newFakeAuthStore() *fakeAuthStore {
+	return &fakeAuthStore{
+		users:   make(map[string]*auth.User),
+		tokens:  make(map[string]string),
+		revoked: make(map[string]bool),
+	}
+}
+
+func (f *fakeAuthStore) CreateUser(_ context.Context, email, passwordHash string) (*auth.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, exists := f.users[email]; exists {
+		return nil, errdefs.NewConflict(fmt.Errorf("e-mail já cadastrado"))
+	}
+	f.nextID++
+	u := &auth.User{ID: fmt.Sprintf("user-%d", f.nextID), Email: email, PasswordHash: passwordHash, CreatedAt: time.Now()}
+	f.users[email] = u
+	return u, nil
+}
+
+func (f *fakeAuthStore) GetUserByEmail(_ context.Context, email string) (*auth.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.users[email]
+	if !ok {
+		return nil, errdefs.NewNotFound(fmt.Errorf("usuário não encontrado"))
+	}
+	return u, nil
+}
+
+func (f *fakeAuthStore) CreateToken(_ context.Context, userID string) (*auth.Token, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tok := fmt.Sprintf("token-%s-%d", userID, len(f.tokens))
+	f.tokens[tok] = userID
+	return &auth.Token{Token: tok, UserID: userID, CreatedAt: time.Now()}, nil
+}
+
+func (f *fakeAuthStore) GetPrincipalByToken(_ context.Context, token string) (*auth.Principal, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	userID, ok := f.tokens[token]
+	if !ok || f.revoked[token] {
+		return nil, errdefs.NewNotFound(fmt.Errorf("token inválido"))
+	}
+	return &auth.Principal{UserID: userID}, nil
+}
+
+func (f *fakeAuthStore) RevokeToken(_ context.Context, token string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.tokens[token]; !ok {
+		return errdefs.NewNotFound(fmt.Errorf("token inválido"))
+	}
+	f.revoked[token] = true
+	return nil
+}
+
+// fakeTodoStore é uma implementação em memória de storage.Store, filtrando
+// por ownerID como faria PostgresStore com a coluna owner_id.
+type fakeTodoStore struct {
+	mu     sync.Mutex
+	nextID int
+	todos  map[string]todo.Todo
+}
+
+func newFakeTodoStore() *fakeTodoStore {
+	return &fakeTodoStore{todos: make(map[string]todo.Todo)}
+}
+
+func (f *fakeTodoStore) CreateTodo(_ context.Context, ownerID string, t todo.Todo) (*todo.Todo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	t.ID = fmt.Sprintf("todo-%d", f.nextID)
+	t.OwnerID = ownerID
+	t.CreatedAt = time.Now()
+	t.UpdatedAt = t.CreatedAt
+	f.todos[t.ID] = t
+	return &t, nil
+}
+
+func (f *fakeTodoStore) GetTodo(_ context.Context, ownerID, id string) (*todo.Todo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.todos[id]
+	if !ok || t.OwnerID != ownerID {
+		return nil, errdefs.NewNotFound(fmt.Errorf("todo com id '%s' não encontrado", id))
+	}
+	return &t, nil
+}
+
+func (f *fakeTodoStore) ListTodos(_ context.Context, ownerID string, opts storage.ListOptions) (*storage.ListResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var matched []todo.Todo
+	for _, t := range f.todos {
+		if t.OwnerID != ownerID {
+			continue
+		}
+		if opts.Completed != nil && t.Completed != *opts.Completed {
+			continue
+		}
+		if opts.Query != "" && !strings.Contains(t.Title, opts.Query) {
+			continue
+		}
+		matched = append(matched, t)
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].CreatedAt.Before(matched[j].CreatedAt) })
+
+	total := len(matched)
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = storage.DefaultPageSize
+	}
+	page := opts.Page
+	if page <= 0 {
+		page = 1
+	}
+
+	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+
+	return &storage.ListResult{Items: matched[start:end], Total: total}, nil
+}
+
+func (f *fakeTodoStore) UpdateTodo(_ context.Context, ownerID, id string, t todo.Todo) (*todo.Todo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	existing, ok := f.todos[id]
+	if !ok || existing.OwnerID != ownerID {
+		return nil, errdefs.NewNotFound(fmt.Errorf("todo com id '%s' não encontrado", id))
+	}
+	existing.Title = t.Title
+	existing.Description = t.Description
+	existing.Completed = t.Completed
+	existing.UpdatedAt = time.Now()
+	f.todos[id] = existing
+	return &existing, nil
+}
+
+func (f *fakeTodoStore) DeleteTodo(_ context.Context, ownerID, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	existing, ok := f.todos[id]
+	if !ok || existing.OwnerID != ownerID {
+		return errdefs.NewNotFound(fmt.Errorf("todo com id '%s' não encontrado", id))
+	}
+	delete(f.todos, id)
+	return nil
+}
+
+func newIsolationTestServer() *httptest.Server {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	authServer := auth.NewServer(logger, newFakeAuthStore())
+	broker := operations.NewBroker()
+	apiServer := NewAPIServer(":0", logger, newFakeTodoStore(), operations.NewRegistry(broker), broker)
+
+	mux := http.NewServeMux()
+	authServer.RegisterRoutes(mux)
+	apiServer.RegisterRoutes(mux, authServer.AuthRequired)
+
+	return httptest.NewServer(mux)
+}
+
+func registerAndLogin(t *testing.T, baseURL, email string) string {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"email": email, "password": "senha-123"})
+	resp, err := http.Post(baseURL+"/register", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("falha ao registrar: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("register: status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+
+	resp, err = http.Post(baseURL+"/login", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("falha ao logar: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("login: status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var loginResp struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&loginResp); err != nil {
+		t.Fatalf("falha ao decodificar resposta de login: %v", err)
+	}
+	return loginResp.Token
+}
+
+func authedRequest(t *testing.T, method, url, token string, body []byte) *http.Response {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatalf("falha ao criar requisição: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("falha ao executar requisição: %v", err)
+	}
+	return resp
+}
+
+func TestCrossUserIsolation(t *testing.T) {
+	srv := newIsolationTestServer()
+	defer srv.Close()
+
+	tokenA := registerAndLogin(t, srv.URL, "alice@example.com")
+	tokenB := registerAndLogin(t, srv.URL, "bob@example.com")
+
+	createBody, _ := json.Marshal(map[string]string{"title": "segredo da alice"})
+	resp := authedRequest(t, http.MethodPost, srv.URL+"/todos", tokenA, createBody)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create: status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	var created todo.Todo
+	json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+
+	// Bob não deve conseguir ver o todo da Alice; deve ser um 404 (não 403)
+	// para não vazar que o recurso existe.
+	resp = authedRequest(t, http.MethodGet, srv.URL+"/todos/"+created.ID, tokenB, nil)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("get de outro usuário: status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+
+	// Alice consegue ver o próprio todo.
+	resp2 := authedRequest(t, http.MethodGet, srv.URL+"/todos/"+created.ID, tokenA, nil)
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusOK {
+		t.Fatalf("get do dono: status = %d, want %d", resp2.StatusCode, http.StatusOK)
+	}
+}
+
+func TestAuthRequiredRejectsMissingToken(t *testing.T) {
+	srv := newIsolationTestServer()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/todos")
+	if err != nil {
+		t.Fatalf("falha ao executar requisição: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/luiszkm/go-todolist/internal/api/versioning"
+	"github.com/luiszkm/go-todolist/internal/storage"
+	"github.com/luiszkm/go-todolist/internal/todo"
+)
+
+// listTodosResponse é o envelope retornado por GET /todos, trazendo os
+// metadados de paginação junto com os itens da página atual. Items carrega a
+// representação de fio da versão negociada (versioning.ToWireList), não
+// []todo.Todo diretamente.
+type listTodosResponse struct {
+	Items      any    `json:"items"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// parseListOptions traduz os parâmetros de query de GET /todos
+// (completed, q, page, page_size, sort, after, use_fts) para um
+// storage.ListOptions.
+func parseListOptions(query url.Values) (storage.ListOptions, error) {
+	var opts storage.ListOptions
+
+	if v := query.Get("completed"); v != "" {
+		completed, err := strconv.ParseBool(v)
+		if err != nil {
+			return opts, fmt.Errorf("parâmetro 'completed' inválido: %w", err)
+		}
+		opts.Completed = &completed
+	}
+
+	opts.Query = query.Get("q")
+	opts.UseFTS = query.Get("use_fts") == "true"
+
+	if v := query.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return opts, fmt.Errorf("parâmetro 'page' inválido: %q", v)
+		}
+		opts.Page = page
+	}
+
+	if v := query.Get("page_size"); v != "" {
+		pageSize, err := strconv.Atoi(v)
+		if err != nil || pageSize < 1 {
+			return opts, fmt.Errorf("parâmetro 'page_size' inválido: %q", v)
+		}
+		opts.PageSize = pageSize
+	}
+
+	if v := query.Get("sort"); v != "" {
+		for _, field := range strings.Split(v, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			desc := strings.HasPrefix(field, "-")
+			opts.Sort = append(opts.Sort, storage.SortField{
+				Field: strings.TrimPrefix(field, "-"),
+				Desc:  desc,
+			})
+		}
+	}
+
+	if v := query.Get("after"); v != "" {
+		cursor, err := storage.DecodeCursor(v)
+		if err != nil {
+			return opts, fmt.Errorf("parâmetro 'after' inválido: %w", err)
+		}
+		opts.After = &cursor
+	}
+
+	return opts, nil
+}
+
+func (s *APIServer) handleListTodos(w http.ResponseWriter, r *http.Request) {
+	owner, ok := ownerID(r)
+	if !ok {
+		respondWithError(w, s.logger, http.StatusUnauthorized, "Não autorizado")
+		return
+	}
+
+	opts, err := parseListOptions(r.URL.Query())
+	if err != nil {
+		respondWithError(w, s.logger, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := s.store.ListTodos(r.Context(), owner, opts)
+	if err != nil {
+		s.respondWithStoreErr(w, "Falha ao listar to-dos", err)
+		return
+	}
+
+	items := result.Items
+	if items == nil {
+		items = []todo.Todo{}
+	}
+
+	page := opts.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = storage.DefaultPageSize
+	}
+
+	if result.NextCursor != "" {
+		nextURL := *r.URL
+		q := nextURL.Query()
+		q.Set("after", result.NextCursor)
+		q.Del("page")
+		nextURL.RawQuery = q.Encode()
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", nextURL.RequestURI()))
+	} else if opts.After == nil && page*pageSize < result.Total {
+		nextURL := *r.URL
+		q := nextURL.Query()
+		q.Set("page", strconv.Itoa(page+1))
+		nextURL.RawQuery = q.Encode()
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", nextURL.RequestURI()))
+	}
+
+	respondWithJSON(w, http.StatusOK, listTodosResponse{
+		Items:      versioning.ToWireList(versioning.FromContext(r.Context()), items),
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      result.Total,
+		NextCursor: result.NextCursor,
+	})
+}
@@ -0,0 +1,127 @@
+package versioning
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/luiszkm/go-todolist/internal/todo"
+)
+
+// Status enum usado pela representação de fio da v2, substituindo o
+// booleano "completed" da v1.
+const (
+	statusPending   = "pending"
+	statusCompleted = "completed"
+)
+
+// TodoV1 é a representação de fio original, preservada para clientes que
+// ainda não migraram para a v2.
+type TodoV1 struct {
+	ID          string    `json:"id"`
+	OwnerID     string    `json:"ownerId"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Completed   bool      `json:"completed"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// TodoV2 é a representação de fio atual: troca "completed" por um enum
+// "status" e adiciona "tags".
+type TodoV2 struct {
+	ID          string    `json:"id"`
+	OwnerID     string    `json:"ownerId"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Status      string    `json:"status"`
+	Tags        []string  `json:"tags,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func toV1(t todo.Todo) TodoV1 {
+	return TodoV1{
+		ID:          t.ID,
+		OwnerID:     t.OwnerID,
+		Title:       t.Title,
+		Description: t.Description,
+		Completed:   t.Completed,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+func toV2(t todo.Todo) TodoV2 {
+	status := statusPending
+	if t.Completed {
+		status = statusCompleted
+	}
+	return TodoV2{
+		ID:          t.ID,
+		OwnerID:     t.OwnerID,
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      status,
+		Tags:        t.Tags,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+// ToWire converte o modelo canônico todo.Todo para a representação de fio
+// da versão v.
+func ToWire(v Version, t todo.Todo) any {
+	if v == V2 {
+		return toV2(t)
+	}
+	return toV1(t)
+}
+
+// ToWireList converte uma lista de todo.Todo para a representação de fio de
+// v, preservando uma lista vazia em vez de nula.
+func ToWireList(v Version, todos []todo.Todo) any {
+	if v == V2 {
+		out := make([]TodoV2, len(todos))
+		for i, t := range todos {
+			out[i] = toV2(t)
+		}
+		return out
+	}
+
+	out := make([]TodoV1, len(todos))
+	for i, t := range todos {
+		out[i] = toV1(t)
+	}
+	return out
+}
+
+// DecodeTodo decodifica o corpo de uma requisição de criação/atualização de
+// todo através da representação de fio da versão v, devolvendo o modelo
+// canônico. Um cliente v1 continua podendo enviar apenas title/description/
+// completed: os campos novos da v2 (status, tags) simplesmente não existem
+// no payload e assumem seus valores zero.
+func DecodeTodo(body io.Reader, v Version) (todo.Todo, error) {
+	if v == V2 {
+		var w TodoV2
+		if err := json.NewDecoder(body).Decode(&w); err != nil {
+			return todo.Todo{}, err
+		}
+		return todo.Todo{
+			Title:       w.Title,
+			Description: w.Description,
+			Completed:   w.Status == statusCompleted,
+			Tags:        w.Tags,
+		}, nil
+	}
+
+	var w TodoV1
+	if err := json.NewDecoder(body).Decode(&w); err != nil {
+		return todo.Todo{}, err
+	}
+	return todo.Todo{
+		Title:       w.Title,
+		Description: w.Description,
+		Completed:   w.Completed,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package versioning
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/luiszkm/go-todolist/internal/todo"
+)
+
+func TestToWireV1HasCompletedBoolean(t *testing.T) {
+	in := todo.Todo{ID: "1", Title: "leite", Completed: true, Tags: []string{"casa"}}
+
+	wire, ok := ToWire(V1, in).(TodoV1)
+	if !ok {
+		t.Fatalf("ToWire(V1, ...) não retornou TodoV1, got %T", wire)
+	}
+	if !wire.Completed {
+		t.Error("TodoV1.Completed deveria ser true")
+	}
+}
+
+func TestToWireV2HasStatusEnumAndTags(t *testing.T) {
+	in := todo.Todo{ID: "1", Title: "leite", Completed: true, Tags: []string{"casa"}}
+
+	wire, ok := ToWire(V2, in).(TodoV2)
+	if !ok {
+		t.Fatalf("ToWire(V2, ...) não retornou TodoV2, got %T", wire)
+	}
+	if wire.Status != statusCompleted {
+		t.Errorf("TodoV2.Status = %q, want %q", wire.Status, statusCompleted)
+	}
+	if len(wire.Tags) != 1 || wire.Tags[0] != "casa" {
+		t.Errorf("TodoV2.Tags = %v, want [casa]", wire.Tags)
+	}
+}
+
+func TestDecodeTodoV1IgnoresUnknownFields(t *testing.T) {
+	body := strings.NewReader(`{"title":"leite","completed":true,"status":"pending","tags":["casa"]}`)
+	got, err := DecodeTodo(body, V1)
+	if err != nil {
+		t.Fatalf("DecodeTodo() erro inesperado: %v", err)
+	}
+	if got.Title != "leite" || !got.Completed {
+		t.Errorf("DecodeTodo() = %+v, want title=leite completed=true", got)
+	}
+}
+
+func TestDecodeTodoV2MapsStatusToCompleted(t *testing.T) {
+	body := strings.NewReader(`{"title":"leite","status":"completed","tags":["casa"]}`)
+	got, err := DecodeTodo(body, V2)
+	if err != nil {
+		t.Fatalf("DecodeTodo() erro inesperado: %v", err)
+	}
+	if !got.Completed {
+		t.Error("DecodeTodo() deveria mapear status=completed para Completed=true")
+	}
+	if len(got.Tags) != 1 || got.Tags[0] != "casa" {
+		t.Errorf("DecodeTodo().Tags = %v, want [casa]", got.Tags)
+	}
+}
+
+func TestToWireListPreservesEmptySlice(t *testing.T) {
+	out := ToWireList(V2, []todo.Todo{})
+	list, ok := out.([]TodoV2)
+	if !ok {
+		t.Fatalf("ToWireList(V2, []) não retornou []TodoV2, got %T", out)
+	}
+	if list == nil {
+		t.Error("ToWireList(V2, []) não deveria retornar nil")
+	}
+}
@@ -0,0 +1,86 @@
+// Package versioning permite que o binário da API atenda múltiplas versões
+// de representação de todo.Todo ao mesmo tempo, no mesmo espírito de
+// versionamento de API usado por runtimes de container: cada versão tem um
+// transformador próprio entre o modelo canônico e a representação de fio,
+// e clientes escolhem a versão via prefixo de URL ou header Accept.
+package versioning
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Version identifica uma versão suportada da API.
+type Version string
+
+const (
+	V1 Version = "v1"
+	V2 Version = "v2"
+
+	// DefaultVersion é usada quando a requisição não especifica uma versão.
+	DefaultVersion = V2
+	// MinVersion é a versão mais antiga ainda suportada.
+	MinVersion = V1
+)
+
+// Supported lista, em ordem, todas as versões que este binário entende.
+func Supported() []Version {
+	return []Version{V1, V2}
+}
+
+func isSupported(v Version) bool {
+	for _, s := range Supported() {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+// WithVersion retorna uma cópia de ctx carregando v.
+func WithVersion(ctx context.Context, v Version) context.Context {
+	return context.WithValue(ctx, contextKey{}, v)
+}
+
+// FromContext recupera a versão negociada para a requisição atual,
+// retornando DefaultVersion se nenhuma tiver sido injetada.
+func FromContext(ctx context.Context) Version {
+	if v, ok := ctx.Value(contextKey{}).(Version); ok {
+		return v
+	}
+	return DefaultVersion
+}
+
+// acceptVersionRe extrai a versão de um header Accept no formato
+// "application/vnd.todolist.vN+json".
+var acceptVersionRe = regexp.MustCompile(`application/vnd\.todolist\.(v\d+)\+json`)
+
+// NegotiateVersion resolve a versão da API para r, priorizando o prefixo da
+// URL (/v1/...) sobre o header Accept, e caindo para DefaultVersion quando
+// nenhum dos dois indica uma versão suportada.
+func NegotiateVersion(r *http.Request) Version {
+	if strings.HasPrefix(r.URL.Path, "/v1/") {
+		return V1
+	}
+
+	if m := acceptVersionRe.FindStringSubmatch(r.Header.Get("Accept")); m != nil {
+		if v := Version(m[1]); isSupported(v) {
+			return v
+		}
+	}
+
+	return DefaultVersion
+}
+
+// Middleware injeta a versão negociada para a requisição no contexto, para
+// que handlers e o codec de (de)serialização possam branchear sobre ela.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := WithVersion(r.Context(), NegotiateVersion(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,56 @@
+package versioning
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		accept string
+		want   Version
+	}{
+		{name: "prefixo /v1/ vence", path: "/v1/todos", accept: "application/vnd.todolist.v2+json", want: V1},
+		{name: "accept header v1", path: "/todos", accept: "application/vnd.todolist.v1+json", want: V1},
+		{name: "accept header v2", path: "/todos", accept: "application/vnd.todolist.v2+json", want: V2},
+		{name: "accept header desconhecido cai para o default", path: "/todos", accept: "application/vnd.todolist.v9+json", want: DefaultVersion},
+		{name: "sem indicação nenhuma usa o default", path: "/todos", accept: "", want: DefaultVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if got := NegotiateVersion(req); got != tt.want {
+				t.Errorf("NegotiateVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromContextDefaultsWhenMissing(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+	if got := FromContext(req.Context()); got != DefaultVersion {
+		t.Errorf("FromContext() sem versão no contexto = %q, want %q", got, DefaultVersion)
+	}
+}
+
+func TestMiddlewareInjectsNegotiatedVersion(t *testing.T) {
+	var got Version
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/todos", nil)
+	rec := httptest.NewRecorder()
+	Middleware(next).ServeHTTP(rec, req)
+
+	if got != V1 {
+		t.Errorf("versão injetada no contexto = %q, want %q", got, V1)
+	}
+}
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Server encapsula as dependências dos handlers de autenticação, no mesmo
+// espírito de api.APIServer.
+type Server struct {
+	logger *slog.Logger
+	store  Store
+}
+
+// NewServer cria um novo Server de autenticação.
+func NewServer(logger *slog.Logger, store Store) *Server {
+	return &Server{logger: logger, store: store}
+}
+
+// RegisterRoutes registra as rotas de autenticação no mux.
+func (s *Server) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/register", s.handleRegister)
+	mux.HandleFunc("/login", s.handleLogin)
+	mux.HandleFunc("/tokens/revoke", s.handleRevokeToken)
+}
+
+type registerRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, s.logger, http.StatusMethodNotAllowed, "Método não permitido")
+		return
+	}
+
+	var req registerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, s.logger, http.StatusBadRequest, "Payload da requisição inválido")
+		return
+	}
+
+	if req.Email == "" || req.Password == "" {
+		respondWithError(w, s.logger, http.StatusBadRequest, "E-mail e senha são obrigatórios")
+		return
+	}
+
+	hash, err := HashPassword(req.Password)
+	if err != nil {
+		respondWithError(w, s.logger, http.StatusInternalServerError, "Falha ao processar a senha")
+		return
+	}
+
+	user, err := s.store.CreateUser(r.Context(), req.Email, hash)
+	if err != nil {
+		code, msg := httpStatusFromErr(err)
+		respondWithError(w, s.logger, code, "Falha ao registrar usuário: "+msg)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, user)
+}
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type loginResponse struct {
+	Token string `json:"token"`
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, s.logger, http.StatusMethodNotAllowed, "Método não permitido")
+		return
+	}
+
+	var req loginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, s.logger, http.StatusBadRequest, "Payload da requisição inválido")
+		return
+	}
+
+	user, err := s.store.GetUserByEmail(r.Context(), req.Email)
+	if err != nil {
+		// Não diferenciamos "e-mail não existe" de "senha errada" para não
+		// vazar quais e-mails estão cadastrados.
+		respondWithError(w, s.logger, http.StatusUnauthorized, "Credenciais inválidas")
+		return
+	}
+
+	if !VerifyPassword(user.PasswordHash, req.Password) {
+		respondWithError(w, s.logger, http.StatusUnauthorized, "Credenciais inválidas")
+		return
+	}
+
+	token, err := s.store.CreateToken(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, s.logger, http.StatusInternalServerError, "Falha ao emitir token")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, loginResponse{Token: token.Token})
+}
+
+func (s *Server) handleRevokeToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondWithError(w, s.logger, http.StatusMethodNotAllowed, "Método não permitido")
+		return
+	}
+
+	token, ok := bearerToken(r)
+	if !ok {
+		respondWithError(w, s.logger, http.StatusUnauthorized, "Token de acesso ausente")
+		return
+	}
+
+	if err := s.store.RevokeToken(r.Context(), token); err != nil {
+		code, msg := httpStatusFromErr(err)
+		respondWithError(w, s.logger, code, "Falha ao revogar token: "+msg)
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// bearerToken extrai o token do cabeçalho "Authorization: Bearer <token>".
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return "", false
+	}
+	token := strings.TrimPrefix(header, prefix)
+	if token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// AuthRequired é uma middleware que exige um token de acesso válido via
+// "Authorization: Bearer <token>", injetando o Principal resolvido no
+// contexto da requisição. Responde 401 quando o token está ausente,
+// malformado, inexistente ou revogado.
+func (s *Server) AuthRequired(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			respondWithError(w, s.logger, http.StatusUnauthorized, "Token de acesso ausente")
+			return
+		}
+
+		principal, err := s.store.GetPrincipalByToken(r.Context(), token)
+		if err != nil {
+			respondWithError(w, s.logger, http.StatusUnauthorized, "Token de acesso inválido")
+			return
+		}
+
+		ctx := WithPrincipal(r.Context(), *principal)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/luiszkm/go-todolist/internal/storage/errdefs"
+)
+
+// respondWithError envia uma resposta de erro JSON. Espelha
+// internal/api/helpers.go, já que auth não pode importar api (api importa
+// auth para ler o Principal do contexto).
+func respondWithError(w http.ResponseWriter, logger *slog.Logger, code int, message string) {
+	logger.Error("resposta de erro da API de autenticação", "status", code, "mensagem", message)
+	respondWithJSON(w, code, map[string]string{"error": message})
+}
+
+// respondWithJSON envia uma resposta JSON.
+func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
+	response, err := json.Marshal(payload)
+	if err != nil {
+		http.Error(w, "Erro ao gerar resposta JSON", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(response)
+}
+
+// httpStatusFromErr mapeia um erro de auth para um status HTTP, delegando
+// para errdefs.HTTPStatus.
+func httpStatusFromErr(err error) (int, string) {
+	return errdefs.HTTPStatus(err)
+}
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/luiszkm/go-todolist/internal/storage/errdefs"
+)
+
+// PostgresStore é a implementação concreta de Store para o PostgreSQL.
+// Compartilha o mesmo *sql.DB usado por storage.PostgresStore.
+type PostgresStore struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewPostgresStore cria um PostgresStore de autenticação a partir de uma
+// conexão já aberta.
+func NewPostgresStore(db *sql.DB, logger *slog.Logger) *PostgresStore {
+	return &PostgresStore{db: db, logger: logger}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateUser insere um novo usuário com a senha já hasheada.
+func (s *PostgresStore) CreateUser(ctx context.Context, email, passwordHash string) (*User, error) {
+	var u User
+	query := `
+		INSERT INTO users (email, password_hash)
+		VALUES ($1, $2)
+		RETURNING id, email, created_at
+	`
+	err := s.db.QueryRowContext(ctx, query, email, passwordHash).Scan(&u.ID, &u.Email, &u.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, errdefs.NewConflict(fmt.Errorf("auth: e-mail '%s' já cadastrado: %w", email, err))
+		}
+		return nil, errdefs.NewInternal(fmt.Errorf("auth: falha ao criar usuário: %w", err))
+	}
+	return &u, nil
+}
+
+// GetUserByEmail busca um usuário pelo e-mail, incluindo o hash da senha
+// para verificação no login.
+func (s *PostgresStore) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	var u User
+	query := `
+		SELECT id, email, password_hash, created_at
+		FROM users
+		WHERE email = $1
+	`
+	err := s.db.QueryRowContext(ctx, query, email).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errdefs.NewNotFound(fmt.Errorf("auth: usuário com e-mail '%s' não encontrado: %w", email, err))
+		}
+		return nil, errdefs.NewInternal(fmt.Errorf("auth: falha ao buscar usuário: %w", err))
+	}
+	return &u, nil
+}
+
+// CreateToken emite um novo token de acesso para userID, persistindo apenas
+// o hash SHA-256 do token.
+func (s *PostgresStore) CreateToken(ctx context.Context, userID string) (*Token, error) {
+	plain, err := GenerateToken()
+	if err != nil {
+		return nil, errdefs.NewInternal(err)
+	}
+
+	var t Token
+	query := `
+		INSERT INTO tokens (token_hash, user_id)
+		VALUES ($1, $2)
+		RETURNING created_at
+	`
+	err = s.db.QueryRowContext(ctx, query, hashToken(plain), userID).Scan(&t.CreatedAt)
+	if err != nil {
+		return nil, errdefs.NewInternal(fmt.Errorf("auth: falha ao criar token: %w", err))
+	}
+
+	t.Token = plain
+	t.UserID = userID
+	return &t, nil
+}
+
+// GetPrincipalByToken resolve um token em texto plano para o Principal do
+// usuário dono, rejeitando tokens revogados. A busca é feita pelo hash do
+// token (índice único) e o hash retornado é comparado em tempo constante
+// contra o hash calculado, como defesa em profundidade.
+func (s *PostgresStore) GetPrincipalByToken(ctx context.Context, token string) (*Principal, error) {
+	wantHash := hashToken(token)
+
+	var (
+		gotHash string
+		userID  string
+		email   string
+	)
+	query := `
+		SELECT t.token_hash, u.id, u.email
+		FROM tokens t
+		JOIN users u ON u.id = t.user_id
+		WHERE t.token_hash = $1 AND t.revoked_at IS NULL
+	`
+	err := s.db.QueryRowContext(ctx, query, wantHash).Scan(&gotHash, &userID, &email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errdefs.NewNotFound(fmt.Errorf("auth: token inválido ou revogado: %w", err))
+		}
+		return nil, errdefs.NewInternal(fmt.Errorf("auth: falha ao buscar token: %w", err))
+	}
+
+	if !tokensEqual(gotHash, wantHash) {
+		return nil, errdefs.NewNotFound(errors.New("auth: token inválido ou revogado"))
+	}
+
+	return &Principal{UserID: userID, Email: email}, nil
+}
+
+// RevokeToken marca um token como revogado, impedindo seu uso futuro.
+func (s *PostgresStore) RevokeToken(ctx context.Context, token string) error {
+	query := `UPDATE tokens SET revoked_at = NOW() WHERE token_hash = $1 AND revoked_at IS NULL`
+	res, err := s.db.ExecContext(ctx, query, hashToken(token))
+	if err != nil {
+		return errdefs.NewInternal(fmt.Errorf("auth: falha ao revogar token: %w", err))
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errdefs.NewInternal(fmt.Errorf("auth: falha ao verificar linhas afetadas ao revogar token: %w", err))
+	}
+	if rowsAffected == 0 {
+		return errdefs.NewNotFound(errors.New("auth: token inválido ou já revogado"))
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package auth
+
+import "context"
+
+// Principal identifica o usuário autenticado responsável pela requisição
+// atual. É injetado no contexto por AuthRequired e consultado pelos
+// handlers, que repassam seu UserID ao storage para restringir o acesso aos
+// próprios recursos.
+type Principal struct {
+	UserID string
+	Email  string
+}
+
+type principalContextKey struct{}
+
+// WithPrincipal retorna uma cópia de ctx carregando p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+// PrincipalFromContext recupera o Principal injetado por AuthRequired. O
+// segundo valor de retorno é false quando a requisição não foi autenticada.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalContextKey{}).(Principal)
+	return p, ok
+}
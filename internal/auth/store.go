@@ -0,0 +1,18 @@
+package auth
+
+import "context"
+
+// Store define as operações de persistência do subsistema de autenticação.
+// Como storage.Store, é uma interface para permitir troca de implementação
+// (ex.: um mock nos testes).
+type Store interface {
+	CreateUser(ctx context.Context, email, passwordHash string) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	CreateToken(ctx context.Context, userID string) (*Token, error)
+	// GetPrincipalByToken resolve um token de acesso em texto plano para o
+	// Principal do usuário dono do token. Retorna um erro que satisfaz
+	// errdefs.NotFound quando o token não existe ou está revogado. Tokens
+	// não expiram por tempo: só deixam de valer quando revogados.
+	GetPrincipalByToken(ctx context.Context, token string) (*Principal, error)
+	RevokeToken(ctx context.Context, token string) error
+}
@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+)
+
+// tokenBytes é o tamanho, em bytes, da porção aleatória de cada token
+// gerado. 32 bytes (256 bits) é o padrão recomendado para tokens de sessão.
+const tokenBytes = 32
+
+// GenerateToken gera um novo token de acesso opaco, codificado em base64 URL
+// a partir de bytes lidos de crypto/rand.
+func GenerateToken() (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("auth: falha ao gerar token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// tokensEqual compara dois tokens em tempo constante para evitar ataques de
+// timing durante a busca por token.
+func tokensEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
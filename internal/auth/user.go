@@ -0,0 +1,19 @@
+package auth
+
+import "time"
+
+// User é uma conta capaz de autenticar e possuir to-dos.
+type User struct {
+	ID           string    `json:"id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// Token é um token de acesso emitido para um User via login.
+type Token struct {
+	Token     string     `json:"token"`
+	UserID    string     `json:"-"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
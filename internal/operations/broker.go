@@ -0,0 +1,74 @@
+package operations
+
+import "sync"
+
+// subscriberBuffer é o tamanho da fila de cada assinante. Um assinante que
+// não consome eventos rápido o suficiente para esvaziar essa fila é
+// considerado lento e é descartado em vez de travar o publicador.
+const subscriberBuffer = 16
+
+// Event é uma mensagem publicada pelo Broker, consumida pelo endpoint de
+// Server-Sent Events. OwnerID restringe a entrega do evento apenas aos
+// assinantes inscritos com o mesmo dono, para que o dono de uma operação não
+// vaze seu resultado para outros usuários conectados a /events.
+type Event struct {
+	Type    string
+	OwnerID string
+	Data    View
+}
+
+// Broker distribui eventos para múltiplos assinantes (fan-out), com
+// backpressure por conexão: assinantes lentos são descartados em vez de
+// bloquear as demais publicações. Cada assinante só recebe eventos cujo
+// OwnerID corresponde ao ownerID com o qual se inscreveu.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]string // canal -> ownerID
+}
+
+// NewBroker cria um Broker pronto para uso.
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[chan Event]string)}
+}
+
+// Subscribe registra um novo assinante interessado apenas em eventos de
+// ownerID e retorna seu canal de eventos e uma função para cancelar a
+// assinatura.
+func (b *Broker) Subscribe(ownerID string) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = ownerID
+	b.mu.Unlock()
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return ch, cancel
+}
+
+// Publish envia evt para os assinantes cujo ownerID corresponde a
+// evt.OwnerID. Um assinante cuja fila está cheia é considerado lento e é
+// removido e fechado em vez de bloquear.
+func (b *Broker) Publish(evt Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch, ownerID := range b.subscribers {
+		if ownerID != evt.OwnerID {
+			continue
+		}
+		select {
+		case ch <- evt:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Package operations modela ações em lote de longa duração (import em
+// massa, exclusão em massa, exportação) como operações assíncronas, em vez
+// de handlers síncronos que bloqueiam a requisição até o fim do trabalho.
+package operations
+
+import (
+	"sync"
+	"time"
+)
+
+// Status representa a fase do ciclo de vida de uma Operation.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed"
+)
+
+// Operation acompanha o estado de um trabalho assíncrono. Os campos mutáveis
+// são protegidos por mu, pois são lidos pelos handlers HTTP e escritos pela
+// goroutine que executa o trabalho.
+type Operation struct {
+	ID        string
+	OwnerID   string
+	Type      string
+	CreatedAt time.Time
+
+	mu        sync.RWMutex
+	status    Status
+	progress  int
+	result    any
+	err       error
+	updatedAt time.Time
+}
+
+func newOperation(id, ownerID, opType string) *Operation {
+	now := time.Now()
+	return &Operation{
+		ID:        id,
+		OwnerID:   ownerID,
+		Type:      opType,
+		CreatedAt: now,
+		status:    StatusPending,
+		updatedAt: now,
+	}
+}
+
+func (o *Operation) setStatus(s Status) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.status = s
+	o.updatedAt = time.Now()
+}
+
+func (o *Operation) setProgress(p int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.progress = p
+	o.updatedAt = time.Now()
+}
+
+func (o *Operation) setDone(result any) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.status = StatusDone
+	o.result = result
+	o.progress = 100
+	o.updatedAt = time.Now()
+}
+
+func (o *Operation) setFailed(err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.status = StatusFailed
+	o.err = err
+	o.updatedAt = time.Now()
+}
+
+// View é a representação somente-leitura de uma Operation, segura para
+// serialização e para ser enviada a assinantes de eventos.
+type View struct {
+	ID        string    `json:"id"`
+	OwnerID   string    `json:"ownerId"`
+	Type      string    `json:"type"`
+	Status    Status    `json:"status"`
+	Progress  int       `json:"progress"`
+	Result    any       `json:"result,omitempty"`
+	Err       string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// View retorna um retrato consistente do estado atual da operação.
+func (o *Operation) View() View {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	v := View{
+		ID:        o.ID,
+		OwnerID:   o.OwnerID,
+		Type:      o.Type,
+		Status:    o.status,
+		Progress:  o.progress,
+		Result:    o.result,
+		CreatedAt: o.CreatedAt,
+		UpdatedAt: o.updatedAt,
+	}
+	if o.err != nil {
+		v.Err = o.err.Error()
+	}
+	return v
+}
@@ -0,0 +1,102 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Registry mantém as operações em memória e publica suas mudanças de estado
+// no Broker associado.
+type Registry struct {
+	mu     sync.RWMutex
+	ops    map[string]*Operation
+	broker *Broker
+	nextID uint64
+	wg     sync.WaitGroup
+}
+
+// NewRegistry cria um Registry vazio que publica eventos em broker.
+func NewRegistry(broker *Broker) *Registry {
+	return &Registry{
+		ops:    make(map[string]*Operation),
+		broker: broker,
+	}
+}
+
+func (r *Registry) newID() string {
+	n := atomic.AddUint64(&r.nextID, 1)
+	return fmt.Sprintf("op_%d", n)
+}
+
+// Get retorna a operação com o ID informado, se existir.
+func (r *Registry) Get(id string) (*Operation, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	op, ok := r.ops[id]
+	return op, ok
+}
+
+// Run registra uma nova operação do tipo opType pertencente a ownerID e
+// executa fn em uma goroutine separada, publicando uma atualização a cada
+// mudança de estado (incluindo cada chamada ao callback de progresso que fn
+// recebe). ownerID é usado pelo handler de GET /operations/{id} e pelo
+// Broker para restringir a
+// visibilidade da operação a quem a criou.
+func (r *Registry) Run(ownerID, opType string, fn func(progress func(int)) (any, error)) *Operation {
+	op := newOperation(r.newID(), ownerID, opType)
+
+	r.mu.Lock()
+	r.ops[op.ID] = op
+	r.mu.Unlock()
+
+	r.publish(op)
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		op.setStatus(StatusRunning)
+		r.publish(op)
+
+		result, err := fn(func(p int) {
+			op.setProgress(p)
+			r.publish(op)
+		})
+
+		if err != nil {
+			op.setFailed(err)
+		} else {
+			op.setDone(result)
+		}
+		r.publish(op)
+	}()
+
+	return op
+}
+
+func (r *Registry) publish(op *Operation) {
+	if r.broker == nil {
+		return
+	}
+	r.broker.Publish(Event{Type: "operation_updated", OwnerID: op.OwnerID, Data: op.View()})
+}
+
+// Wait bloqueia até que todas as operações em andamento terminem ou até que
+// ctx seja cancelado, o que ocorrer primeiro. Retorna o erro de ctx quando o
+// timeout é atingido antes de todas as operações concluírem.
+func (r *Registry) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
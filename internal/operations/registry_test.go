@@ -0,0 +1,114 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistryRunSuccess(t *testing.T) {
+	broker := NewBroker()
+	sub, cancel := broker.Subscribe("owner-1")
+	defer cancel()
+
+	reg := NewRegistry(broker)
+	op := reg.Run("owner-1", "bulk_create", func(progress func(int)) (any, error) {
+		progress(50)
+		return "ok", nil
+	})
+
+	if err := reg.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() retornou erro inesperado: %v", err)
+	}
+
+	view := op.View()
+	if view.Status != StatusDone {
+		t.Fatalf("Status = %q, want %q", view.Status, StatusDone)
+	}
+	if view.Result != "ok" {
+		t.Fatalf("Result = %v, want %q", view.Result, "ok")
+	}
+
+	got, ok := reg.Get(op.ID)
+	if !ok || got != op {
+		t.Fatalf("Get(%q) não retornou a mesma operação", op.ID)
+	}
+
+	sawDone := false
+	for {
+		select {
+		case evt := <-sub:
+			if evt.Data.Status == StatusDone {
+				sawDone = true
+			}
+		default:
+			if !sawDone {
+				t.Fatal("nenhum evento operation_updated com status done foi publicado")
+			}
+			return
+		}
+	}
+}
+
+func TestRegistryRunFailure(t *testing.T) {
+	reg := NewRegistry(NewBroker())
+	wantErr := errors.New("falha ao processar lote")
+
+	op := reg.Run("owner-1", "bulk_delete", func(progress func(int)) (any, error) {
+		return nil, wantErr
+	})
+
+	if err := reg.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() retornou erro inesperado: %v", err)
+	}
+
+	view := op.View()
+	if view.Status != StatusFailed {
+		t.Fatalf("Status = %q, want %q", view.Status, StatusFailed)
+	}
+	if view.Err != wantErr.Error() {
+		t.Fatalf("Err = %q, want %q", view.Err, wantErr.Error())
+	}
+}
+
+func TestRegistryWaitTimesOut(t *testing.T) {
+	reg := NewRegistry(NewBroker())
+	release := make(chan struct{})
+
+	reg.Run("owner-1", "slow", func(progress func(int)) (any, error) {
+		<-release
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := reg.Wait(ctx); err == nil {
+		t.Fatal("Wait() deveria retornar erro de timeout")
+	}
+
+	close(release)
+}
+
+func TestBrokerDropsSlowSubscriber(t *testing.T) {
+	broker := NewBroker()
+	sub, _ := broker.Subscribe("owner-1")
+
+	// Publica mais eventos do que o buffer do assinante suporta sem que
+	// ninguém leia, forçando o broker a descartá-lo.
+	for i := 0; i < subscriberBuffer+5; i++ {
+		broker.Publish(Event{Type: "operation_updated", OwnerID: "owner-1"})
+	}
+
+	if _, ok := <-sub; !ok {
+		t.Fatal("canal do assinante deveria conter eventos do buffer antes de ser fechado")
+	}
+
+	broker.mu.Lock()
+	remaining := len(broker.subscribers)
+	broker.mu.Unlock()
+	if remaining != 0 {
+		t.Fatalf("esperava que o assinante lento tivesse sido removido, restaram %d", remaining)
+	}
+}
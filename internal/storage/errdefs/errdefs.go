@@ -0,0 +1,162 @@
+// Package errdefs define uma taxonomia de erros tipados usada pela camada de
+// storage e propagada até a API, substituindo a checagem frágil por
+// substring em mensagens de erro. A abordagem segue o padrão adotado pelo
+// Moby/Docker: interfaces pequenas que qualquer erro (mesmo envolvido por
+// camadas intermediárias) pode satisfazer, permitindo que `errors.As`
+// descubra a categoria sem acoplar o chamador à implementação concreta.
+package errdefs
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Sentinelas de cada categoria. errors.Is(err, ErrNotFound) (e o mesmo para
+// as demais) é verdadeiro para qualquer erro criado pelo construtor New*
+// da categoria correspondente, mesmo envolvido por outras camadas.
+var (
+	ErrNotFound        = errors.New("recurso não encontrado")
+	ErrConflict        = errors.New("conflito com o estado atual do recurso")
+	ErrInvalidArgument = errors.New("argumento inválido")
+	ErrUnauthorized    = errors.New("não autorizado")
+	ErrInternal        = errors.New("erro interno")
+)
+
+// NotFound é satisfeita por qualquer erro que represente um recurso ausente.
+type NotFound interface {
+	IsNotFound() bool
+}
+
+// Conflict é satisfeita por qualquer erro que represente um conflito de estado
+// (ex.: violação de unicidade).
+type Conflict interface {
+	IsConflict() bool
+}
+
+// InvalidArgument é satisfeita por qualquer erro de entrada inválida
+// (ex.: violação de chave estrangeira, payload malformado).
+type InvalidArgument interface {
+	IsInvalidArgument() bool
+}
+
+// Unauthorized é satisfeita por qualquer erro de autenticação/autorização.
+type Unauthorized interface {
+	IsUnauthorized() bool
+}
+
+// Internal é satisfeita por qualquer erro interno não mapeável para o cliente.
+type Internal interface {
+	IsInternal() bool
+}
+
+type wrappedError struct {
+	cause error
+	kind  string
+}
+
+func (e *wrappedError) Error() string { return e.cause.Error() }
+func (e *wrappedError) Unwrap() error { return e.cause }
+
+func (e *wrappedError) IsNotFound() bool        { return e.kind == "not_found" }
+func (e *wrappedError) IsConflict() bool        { return e.kind == "conflict" }
+func (e *wrappedError) IsInvalidArgument() bool { return e.kind == "invalid_argument" }
+func (e *wrappedError) IsUnauthorized() bool    { return e.kind == "unauthorized" }
+func (e *wrappedError) IsInternal() bool        { return e.kind == "internal" }
+
+// Is associa cada categoria à sua sentinela para errors.Is.
+func (e *wrappedError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.kind == "not_found"
+	case ErrConflict:
+		return e.kind == "conflict"
+	case ErrInvalidArgument:
+		return e.kind == "invalid_argument"
+	case ErrUnauthorized:
+		return e.kind == "unauthorized"
+	case ErrInternal:
+		return e.kind == "internal"
+	}
+	return false
+}
+
+// NewNotFound envolve cause (tipicamente já formatado com %w para
+// sql.ErrNoRows) em um erro que satisfaz a interface NotFound.
+func NewNotFound(cause error) error {
+	return &wrappedError{cause: cause, kind: "not_found"}
+}
+
+// NewConflict envolve cause em um erro que satisfaz a interface Conflict.
+func NewConflict(cause error) error {
+	return &wrappedError{cause: cause, kind: "conflict"}
+}
+
+// NewInvalidArgument envolve cause em um erro que satisfaz InvalidArgument.
+func NewInvalidArgument(cause error) error {
+	return &wrappedError{cause: cause, kind: "invalid_argument"}
+}
+
+// NewUnauthorized envolve cause em um erro que satisfaz Unauthorized.
+func NewUnauthorized(cause error) error {
+	return &wrappedError{cause: cause, kind: "unauthorized"}
+}
+
+// NewInternal envolve cause em um erro que satisfaz Internal.
+func NewInternal(cause error) error {
+	return &wrappedError{cause: cause, kind: "internal"}
+}
+
+// IsNotFound percorre a cadeia de causas de err (via errors.As) procurando
+// algo que satisfaça NotFound.
+func IsNotFound(err error) bool {
+	var e NotFound
+	return errors.As(err, &e) && e.IsNotFound()
+}
+
+// IsConflict percorre a cadeia de causas de err procurando algo que
+// satisfaça Conflict.
+func IsConflict(err error) bool {
+	var e Conflict
+	return errors.As(err, &e) && e.IsConflict()
+}
+
+// IsInvalidArgument percorre a cadeia de causas de err procurando algo que
+// satisfaça InvalidArgument.
+func IsInvalidArgument(err error) bool {
+	var e InvalidArgument
+	return errors.As(err, &e) && e.IsInvalidArgument()
+}
+
+// IsUnauthorized percorre a cadeia de causas de err procurando algo que
+// satisfaça Unauthorized.
+func IsUnauthorized(err error) bool {
+	var e Unauthorized
+	return errors.As(err, &e) && e.IsUnauthorized()
+}
+
+// IsInternal percorre a cadeia de causas de err procurando algo que
+// satisfaça Internal.
+func IsInternal(err error) bool {
+	var e Internal
+	return errors.As(err, &e) && e.IsInternal()
+}
+
+// HTTPStatus mapeia err para um status HTTP e uma mensagem segura para o
+// cliente, usando esta taxonomia em vez de inspecionar a mensagem do erro.
+// Centralizado aqui para que api e auth (que não podem importar uma da
+// outra) compartilhem uma única implementação em vez de duas cópias que
+// podem divergir.
+func HTTPStatus(err error) (int, string) {
+	switch {
+	case IsNotFound(err):
+		return http.StatusNotFound, "recurso não encontrado"
+	case IsConflict(err):
+		return http.StatusConflict, "conflito com o estado atual do recurso"
+	case IsInvalidArgument(err):
+		return http.StatusBadRequest, "argumento inválido"
+	case IsUnauthorized(err):
+		return http.StatusUnauthorized, "não autorizado"
+	default:
+		return http.StatusInternalServerError, "erro interno"
+	}
+}
@@ -0,0 +1,49 @@
+package errdefs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"direct", NewNotFound(errors.New("x")), true},
+		{"wrapped", fmt.Errorf("camada: %w", NewNotFound(errors.New("x"))), true},
+		{"other kind", NewConflict(errors.New("x")), false},
+		{"plain error", errors.New("x"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNotFound(tt.err); got != tt.want {
+				t.Errorf("IsNotFound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsConflict(t *testing.T) {
+	if !IsConflict(NewConflict(errors.New("x"))) {
+		t.Error("IsConflict() deveria ser true para erro de conflito")
+	}
+	if IsConflict(NewNotFound(errors.New("x"))) {
+		t.Error("IsConflict() deveria ser false para erro de not found")
+	}
+}
+
+func TestIsInvalidArgument(t *testing.T) {
+	if !IsInvalidArgument(NewInvalidArgument(errors.New("x"))) {
+		t.Error("IsInvalidArgument() deveria ser true para erro de argumento inválido")
+	}
+}
+
+func TestIsUnauthorized(t *testing.T) {
+	if !IsUnauthorized(NewUnauthorized(errors.New("x"))) {
+		t.Error("IsUnauthorized() deveria ser true para erro de não autorizado")
+	}
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luiszkm/go-todolist/internal/todo"
+)
+
+// DefaultPageSize e MaxPageSize limitam o tamanho da página quando o
+// cliente não informa ?page_size ou informa um valor fora da faixa aceita.
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 200
+)
+
+// sortableColumns mapeia os nomes de campo aceitos em ?sort= (no mesmo
+// formato usado pelo JSON de todo.Todo) para a coluna real no banco. Isso
+// funciona como whitelist: qualquer nome fora deste mapa é rejeitado antes
+// de chegar perto de um ORDER BY, evitando SQL injection via esse parâmetro.
+var sortableColumns = map[string]string{
+	"title":     "title",
+	"completed": "completed",
+	"createdAt": "created_at",
+	"updatedAt": "updated_at",
+}
+
+// SortField é um campo de ordenação solicitado pelo cliente via ?sort=.
+type SortField struct {
+	Field string // nome no formato usado pelo JSON, ex. "updatedAt"
+	Desc  bool
+}
+
+// column resolve o nome da coluna correspondente a Field. ok é false quando
+// Field não está na whitelist de colunas ordenáveis.
+func (f SortField) column() (col string, ok bool) {
+	col, ok = sortableColumns[f.Field]
+	return col, ok
+}
+
+// Cursor identifica a posição de um registro para paginação por keyset,
+// usando a ordenação padrão (updated_at DESC, id DESC).
+type Cursor struct {
+	UpdatedAt time.Time
+	ID        string
+}
+
+// EncodeCursor codifica c em base64 URL-safe para uso em ?after=.
+func EncodeCursor(c Cursor) string {
+	raw := fmt.Sprintf("%d,%s", c.UpdatedAt.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor decodifica um cursor gerado por EncodeCursor.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("storage: cursor inválido: %w", err)
+	}
+
+	parts := strings.SplitN(string(raw), ",", 2)
+	if len(parts) != 2 {
+		return Cursor{}, fmt.Errorf("storage: cursor inválido: formato inesperado")
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("storage: cursor inválido: %w", err)
+	}
+
+	return Cursor{UpdatedAt: time.Unix(0, nanos), ID: parts[1]}, nil
+}
+
+// ListOptions parametriza ListTodos: filtragem, busca textual, paginação e
+// ordenação.
+type ListOptions struct {
+	// Completed, quando não nil, filtra por tarefas concluídas ou não.
+	Completed *bool
+	// Query é o termo de busca aplicado a título e descrição.
+	Query string
+	// UseFTS troca o ILIKE por plainto_tsquery contra a coluna tsvector
+	// gerada, para bases maiores.
+	UseFTS bool
+
+	// Page e PageSize controlam a paginação por offset. Page é ignorado
+	// quando After não é nil; PageSize continua limitando o tamanho da
+	// página também na paginação por keyset.
+	Page     int
+	PageSize int
+
+	// Sort é a lista de campos de ordenação, na ordem solicitada. Vazio
+	// significa a ordenação padrão (updated_at DESC). Ignorado quando After
+	// não é nil, já que a paginação por keyset depende da ordenação padrão
+	// para funcionar.
+	Sort []SortField
+
+	// After, quando não nil, ativa a paginação por keyset a partir deste
+	// cursor, como alternativa a Page/PageSize para bases grandes.
+	After *Cursor
+}
+
+// ListResult é o retorno de ListTodos: os itens da página atual, o total de
+// registros que atendem ao filtro (ignorando paginação) e o cursor para a
+// próxima página por keyset, quando houver.
+type ListResult struct {
+	Items      []todo.Todo
+	Total      int
+	NextCursor string
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luiszkm/go-todolist/internal/storage/errdefs"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := Cursor{UpdatedAt: time.Now().Truncate(time.Microsecond), ID: "todo-123"}
+
+	got, err := DecodeCursor(EncodeCursor(want))
+	if err != nil {
+		t.Fatalf("DecodeCursor() erro inesperado: %v", err)
+	}
+	if !got.UpdatedAt.Equal(want.UpdatedAt) || got.ID != want.ID {
+		t.Errorf("DecodeCursor() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	if _, err := DecodeCursor("não é base64 válido!!"); err == nil {
+		t.Error("DecodeCursor() deveria falhar para entrada inválida")
+	}
+}
+
+func TestBuildOrderBy(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ListOptions
+		want string
+	}{
+		{
+			name: "sem sort usa o padrão",
+			opts: ListOptions{},
+			want: "updated_at DESC, id DESC",
+		},
+		{
+			name: "after ignora sort e usa o padrão",
+			opts: ListOptions{Sort: []SortField{{Field: "title"}}, After: &Cursor{}},
+			want: "updated_at DESC, id DESC",
+		},
+		{
+			name: "sort válido resolve para a coluna e acrescenta id",
+			opts: ListOptions{Sort: []SortField{{Field: "updatedAt", Desc: true}, {Field: "title"}}},
+			want: "updated_at DESC, title ASC, id ASC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildOrderBy(tt.opts)
+			if err != nil {
+				t.Fatalf("buildOrderBy() erro inesperado: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("buildOrderBy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildOrderByRejectsUnknownColumn(t *testing.T) {
+	_, err := buildOrderBy(ListOptions{Sort: []SortField{{Field: "'; DROP TABLE todos; --"}}})
+	if err == nil {
+		t.Fatal("buildOrderBy() deveria rejeitar um campo de ordenação fora da whitelist")
+	}
+	if !errdefs.IsInvalidArgument(err) {
+		t.Errorf("erro retornado deveria ser InvalidArgument, got %v", err)
+	}
+}
+
+func TestBuildListFilter(t *testing.T) {
+	completed := true
+	clause, args := buildListFilter("owner-1", ListOptions{Completed: &completed, Query: "leite"})
+
+	wantClause := "owner_id = $1 AND completed = $2 AND (title ILIKE $3 OR description ILIKE $3)"
+	if clause != wantClause {
+		t.Errorf("clause = %q, want %q", clause, wantClause)
+	}
+	if len(args) != 3 || args[0] != "owner-1" || args[1] != true || args[2] != "%leite%" {
+		t.Errorf("args = %v, want [owner-1 true %%leite%%]", args)
+	}
+}
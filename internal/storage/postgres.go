@@ -3,21 +3,51 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	_ "github.com/jackc/pgx/v5/stdlib" // Driver do PostgreSQL
+	"github.com/luiszkm/go-todolist/internal/storage/errdefs"
 	"github.com/luiszkm/go-todolist/internal/todo"
 )
 
+// Códigos SQLSTATE do Postgres que mapeamos para erros tipados.
+const (
+	sqlStateUniqueViolation     = "23505"
+	sqlStateForeignKeyViolation = "23503"
+)
+
+// wrapDBErr traduz um erro retornado pelo driver pgx/sql em um erro tipado de
+// errdefs, preservando a causa original via %w.
+func wrapDBErr(err error, notFoundMsg string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errdefs.NewNotFound(fmt.Errorf("%s: %w", notFoundMsg, err))
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case sqlStateUniqueViolation:
+			return errdefs.NewConflict(fmt.Errorf("storage: violação de unicidade: %w", err))
+		case sqlStateForeignKeyViolation:
+			return errdefs.NewInvalidArgument(fmt.Errorf("storage: violação de chave estrangeira: %w", err))
+		}
+	}
+
+	return errdefs.NewInternal(fmt.Errorf("storage: erro inesperado: %w", err))
+}
+
 // Store define a interface para as operações de armazenamento de dados.
 // Usar uma interface nos permite trocar a implementação (ex: para um mock nos testes).
 type Store interface {
-	CreateTodo(ctx context.Context, t todo.Todo) (*todo.Todo, error)
-	GetTodo(ctx context.Context, id string) (*todo.Todo, error)
-	ListTodos(ctx context.Context) ([]todo.Todo, error)
-	UpdateTodo(ctx context.Context, id string, t todo.Todo) (*todo.Todo, error)
-	DeleteTodo(ctx context.Context, id string) error
+	CreateTodo(ctx context.Context, ownerID string, t todo.Todo) (*todo.Todo, error)
+	GetTodo(ctx context.Context, ownerID, id string) (*todo.Todo, error)
+	ListTodos(ctx context.Context, ownerID string, opts ListOptions) (*ListResult, error)
+	UpdateTodo(ctx context.Context, ownerID, id string, t todo.Todo) (*todo.Todo, error)
+	DeleteTodo(ctx context.Context, ownerID, id string) error
 }
 
 // PostgresStore é a implementação concreta da interface Store para o PostgreSQL.
@@ -48,103 +78,234 @@ func (s *PostgresStore) Close() error {
 	return s.db.Close()
 }
 
-// CreateTodo insere uma nova tarefa no banco de dados.
-func (s *PostgresStore) CreateTodo(ctx context.Context, t todo.Todo) (*todo.Todo, error) {
+// DB expõe a conexão subjacente para que outros subsistemas (ex.: auth)
+// compartilhem o mesmo pool em vez de abrir uma conexão separada.
+func (s *PostgresStore) DB() *sql.DB {
+	return s.db
+}
+
+// CreateTodo insere uma nova tarefa no banco de dados, associada a ownerID.
+func (s *PostgresStore) CreateTodo(ctx context.Context, ownerID string, t todo.Todo) (*todo.Todo, error) {
 	query := `
-		INSERT INTO todos (title, description, completed)
-		VALUES ($1, $2, $3)
+		INSERT INTO todos (owner_id, title, description, completed, tags)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at, updated_at
 	`
-	err := s.db.QueryRowContext(ctx, query, t.Title, t.Description, t.Completed).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+	if t.Tags == nil {
+		// A coluna tags é NOT NULL; um slice nil seria enviado como NULL.
+		t.Tags = []string{}
+	}
+	err := s.db.QueryRowContext(ctx, query, ownerID, t.Title, t.Description, t.Completed, t.Tags).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
 	if err != nil {
-		return nil, fmt.Errorf("storage: falha ao criar todo: %w", err)
+		return nil, wrapDBErr(err, "storage: falha ao criar todo")
 	}
+	t.OwnerID = ownerID
 	return &t, nil
 }
 
-// GetTodo busca uma tarefa pelo seu ID.
-func (s *PostgresStore) GetTodo(ctx context.Context, id string) (*todo.Todo, error) {
+// GetTodo busca uma tarefa pelo seu ID, restrita ao dono ownerID. Um todo de
+// outro usuário é tratado como inexistente (404), não como acesso negado,
+// para não vazar a existência do recurso.
+func (s *PostgresStore) GetTodo(ctx context.Context, ownerID, id string) (*todo.Todo, error) {
 	var t todo.Todo
 	query := `
-		SELECT id, title, description, completed, created_at, updated_at
+		SELECT id, owner_id, title, description, completed, tags, created_at, updated_at
 		FROM todos
-		WHERE id = $1
+		WHERE id = $1 AND owner_id = $2
 	`
-	err := s.db.QueryRowContext(ctx, query, id).Scan(&t.ID, &t.Title, &t.Description, &t.Completed, &t.CreatedAt, &t.UpdatedAt)
+	err := s.db.QueryRowContext(ctx, query, id, ownerID).Scan(&t.ID, &t.OwnerID, &t.Title, &t.Description, &t.Completed, &t.Tags, &t.CreatedAt, &t.UpdatedAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("storage: todo com id '%s' não encontrado: %w", id, err)
-		}
-		return nil, fmt.Errorf("storage: falha ao buscar todo: %w", err)
+		return nil, wrapDBErr(err, fmt.Sprintf("storage: todo com id '%s' não encontrado", id))
 	}
 	return &t, nil
 }
 
-// ListTodos retorna todas as tarefas do banco de dados.
-func (s *PostgresStore) ListTodos(ctx context.Context) ([]todo.Todo, error) {
-	query := `
-		SELECT id, title, description, completed, created_at, updated_at
-		FROM todos
-		ORDER BY created_at DESC
-	`
-	rows, err := s.db.QueryContext(ctx, query)
+// buildListFilter monta a cláusula WHERE comum a SELECT e COUNT(*),
+// compartilhada pelas duas consultas de ListTodos para que o total reflita
+// exatamente o mesmo filtro aplicado aos itens da página.
+func buildListFilter(ownerID string, opts ListOptions) (clause string, args []any) {
+	clause = "owner_id = $1"
+	args = []any{ownerID}
+
+	if opts.Completed != nil {
+		args = append(args, *opts.Completed)
+		clause += fmt.Sprintf(" AND completed = $%d", len(args))
+	}
+
+	if opts.Query != "" {
+		args = append(args, opts.Query)
+		if opts.UseFTS {
+			clause += fmt.Sprintf(" AND search_vector @@ plainto_tsquery('portuguese', $%d)", len(args))
+		} else {
+			// Para bases pequenas, ILIKE é suficiente e não exige o índice GIN.
+			args[len(args)-1] = "%" + opts.Query + "%"
+			clause += fmt.Sprintf(" AND (title ILIKE $%d OR description ILIKE $%d)", len(args), len(args))
+		}
+	}
+
+	return clause, args
+}
+
+// buildOrderBy resolve opts.Sort para uma cláusula ORDER BY, validando cada
+// campo contra a whitelist de sortableColumns. Quando opts.Sort está vazio,
+// usa a ordenação padrão. A paginação por keyset (opts.After) só é
+// consistente com a ordenação padrão, então opts.Sort é ignorado nesse caso.
+func buildOrderBy(opts ListOptions) (string, error) {
+	if opts.After != nil || len(opts.Sort) == 0 {
+		return "updated_at DESC, id DESC", nil
+	}
+
+	parts := make([]string, 0, len(opts.Sort)+1)
+	for _, f := range opts.Sort {
+		col, ok := f.column()
+		if !ok {
+			return "", errdefs.NewInvalidArgument(fmt.Errorf("storage: campo de ordenação inválido: %q", f.Field))
+		}
+		dir := "ASC"
+		if f.Desc {
+			dir = "DESC"
+		}
+		parts = append(parts, col+" "+dir)
+	}
+	// id como critério de desempate garante uma ordem estável entre páginas.
+	parts = append(parts, "id ASC")
+
+	return strings.Join(parts, ", "), nil
+}
+
+// ListTodos retorna as tarefas pertencentes a ownerID que atendem a opts,
+// com o total de registros (ignorando paginação) calculado na mesma
+// transação para garantir consistência entre items e total.
+func (s *PostgresStore) ListTodos(ctx context.Context, ownerID string, opts ListOptions) (*ListResult, error) {
+	orderBy, err := buildOrderBy(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, args := buildListFilter(ownerID, opts)
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
-		return nil, fmt.Errorf("storage: falha ao listar todos: %w", err)
+		return nil, wrapDBErr(err, "storage: falha ao iniciar transação para listar todos")
+	}
+	defer tx.Rollback()
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM todos WHERE " + filter
+	if err := tx.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, wrapDBErr(err, "storage: falha ao contar todos")
+	}
+
+	selectArgs := append([]any{}, args...)
+	query := "SELECT id, owner_id, title, description, completed, tags, created_at, updated_at FROM todos WHERE " + filter
+
+	if opts.After != nil {
+		selectArgs = append(selectArgs, opts.After.UpdatedAt, opts.After.ID)
+		query += fmt.Sprintf(" AND (updated_at, id) < ($%d, $%d)", len(selectArgs)-1, len(selectArgs))
+	}
+
+	query += " ORDER BY " + orderBy
+
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	// Busca um registro a mais que o necessário para saber se existe uma
+	// próxima página sem uma segunda viagem ao banco.
+	selectArgs = append(selectArgs, pageSize+1)
+	query += fmt.Sprintf(" LIMIT $%d", len(selectArgs))
+
+	if opts.After == nil {
+		page := opts.Page
+		if page <= 0 {
+			page = 1
+		}
+		selectArgs = append(selectArgs, (page-1)*pageSize)
+		query += fmt.Sprintf(" OFFSET $%d", len(selectArgs))
+	}
+
+	rows, err := tx.QueryContext(ctx, query, selectArgs...)
+	if err != nil {
+		return nil, wrapDBErr(err, "storage: falha ao listar todos")
 	}
 	defer rows.Close()
 
 	var todos []todo.Todo
 	for rows.Next() {
 		var t todo.Todo
-		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.Completed, &t.CreatedAt, &t.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("storage: falha ao escanear linha do todo: %w", err)
+		if err := rows.Scan(&t.ID, &t.OwnerID, &t.Title, &t.Description, &t.Completed, &t.Tags, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, wrapDBErr(err, "storage: falha ao escanear linha do todo")
 		}
 		todos = append(todos, t)
 	}
-
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("storage: erro durante iteração das linhas de todos: %w", err)
+		return nil, wrapDBErr(err, "storage: erro durante iteração das linhas de todos")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, wrapDBErr(err, "storage: falha ao confirmar transação de listagem")
 	}
 
-	return todos, nil
+	result := &ListResult{Total: total}
+
+	hasNext := len(todos) > pageSize
+	if hasNext {
+		todos = todos[:pageSize]
+	}
+	result.Items = todos
+
+	// O cursor de keyset só é válido sob a ordenação padrão (updated_at, id),
+	// então só o calculamos quando essa é a ordenação em uso.
+	usingDefaultOrder := opts.After != nil || len(opts.Sort) == 0
+	if hasNext && usingDefaultOrder && len(todos) > 0 {
+		last := todos[len(todos)-1]
+		result.NextCursor = EncodeCursor(Cursor{UpdatedAt: last.UpdatedAt, ID: last.ID})
+	}
+
+	return result, nil
 }
 
-// UpdateTodo atualiza uma tarefa existente.
-func (s *PostgresStore) UpdateTodo(ctx context.Context, id string, t todo.Todo) (*todo.Todo, error) {
+// UpdateTodo atualiza uma tarefa existente pertencente a ownerID.
+func (s *PostgresStore) UpdateTodo(ctx context.Context, ownerID, id string, t todo.Todo) (*todo.Todo, error) {
 	query := `
 		UPDATE todos
-		SET title = $1, description = $2, completed = $3, updated_at = NOW()
-		WHERE id = $4
-		RETURNING id, title, description, completed, created_at, updated_at
+		SET title = $1, description = $2, completed = $3, tags = $4, updated_at = NOW()
+		WHERE id = $5 AND owner_id = $6
+		RETURNING id, owner_id, title, description, completed, tags, created_at, updated_at
 	`
+	if t.Tags == nil {
+		// A coluna tags é NOT NULL; um slice nil seria enviado como NULL.
+		t.Tags = []string{}
+	}
 	var updatedTodo todo.Todo
-	err := s.db.QueryRowContext(ctx, query, t.Title, t.Description, t.Completed, id).Scan(
-		&updatedTodo.ID, &updatedTodo.Title, &updatedTodo.Description, &updatedTodo.Completed, &updatedTodo.CreatedAt, &updatedTodo.UpdatedAt,
+	err := s.db.QueryRowContext(ctx, query, t.Title, t.Description, t.Completed, t.Tags, id, ownerID).Scan(
+		&updatedTodo.ID, &updatedTodo.OwnerID, &updatedTodo.Title, &updatedTodo.Description, &updatedTodo.Completed, &updatedTodo.Tags, &updatedTodo.CreatedAt, &updatedTodo.UpdatedAt,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("storage: impossível atualizar, todo com id '%s' não encontrado: %w", id, err)
-		}
-		return nil, fmt.Errorf("storage: falha ao atualizar todo: %w", err)
+		return nil, wrapDBErr(err, fmt.Sprintf("storage: impossível atualizar, todo com id '%s' não encontrado", id))
 	}
 	return &updatedTodo, nil
 }
 
-// DeleteTodo remove uma tarefa do banco de dados.
-func (s *PostgresStore) DeleteTodo(ctx context.Context, id string) error {
-	query := `DELETE FROM todos WHERE id = $1`
-	res, err := s.db.ExecContext(ctx, query, id)
+// DeleteTodo remove uma tarefa pertencente a ownerID.
+func (s *PostgresStore) DeleteTodo(ctx context.Context, ownerID, id string) error {
+	query := `DELETE FROM todos WHERE id = $1 AND owner_id = $2`
+	res, err := s.db.ExecContext(ctx, query, id, ownerID)
 	if err != nil {
-		return fmt.Errorf("storage: falha ao deletar todo: %w", err)
+		return wrapDBErr(err, "storage: falha ao deletar todo")
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("storage: falha ao verificar linhas afetadas ao deletar: %w", err)
+		return wrapDBErr(err, "storage: falha ao verificar linhas afetadas ao deletar")
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("storage: impossível deletar, todo com id '%s' não encontrado", id)
+		return errdefs.NewNotFound(fmt.Errorf("storage: impossível deletar, todo com id '%s' não encontrado", id))
 	}
 
 	return nil
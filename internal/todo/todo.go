@@ -3,10 +3,14 @@ package todo
 import "time"
 
 type Todo struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description,omitempty"`
-	Completed   bool      `json:"completed"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          string `json:"id"`
+	OwnerID     string `json:"ownerId"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Completed   bool   `json:"completed"`
+	// Tags é persistido para qualquer versão, mas só a representação de
+	// fio da v2 da API o inclui; ver internal/api/versioning.
+	Tags      []string  `json:"tags,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }